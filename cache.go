@@ -2,14 +2,25 @@ package cache
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"math/rand"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/redis/rueidis"
 	"github.com/rs/zerolog/log"
-	rediscache "github.com/go-redis/cache/v7"
-	"github.com/go-redis/redis/v7"
-	"github.com/vmihailenco/msgpack/v4"
+	"github.com/sony/gobreaker"
+	"github.com/vmihailenco/go-tinylfu"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -24,11 +35,29 @@ const (
 	defaultExpiration = time.Hour * 2
 	// defaultRetries is the default number of times a cache request is retried
 	defaultRetries = 10
+	// defaultLocalCacheTTL is how long a value lives in the local tier before
+	// it is considered stale and must be refetched from redis.
+	defaultLocalCacheTTL = time.Minute * 5
+	// defaultBaseDelay is the first backoff delay between retried operations.
+	defaultBaseDelay = time.Millisecond * 10
+	// defaultMaxDelay caps the backoff delay between retried operations.
+	defaultMaxDelay = time.Second
+	// defaultMaxFailures is the number of consecutive failures that trips
+	// the circuit breaker open.
+	defaultMaxFailures = 5
+	// defaultBreakerTimeout is how long the circuit breaker stays open
+	// before allowing a single trial request through.
+	defaultBreakerTimeout = time.Second * 30
 )
 
 // ErrCacheMiss is returned when a key is not contained in cache.
 var ErrCacheMiss = errors.New("cache: key is missing")
 
+// ErrCacheUnavailable is returned instead of retrying when the circuit
+// breaker wrapping redis is open, so callers can degrade to the backing
+// store immediately rather than piling up retries during a brownout.
+var ErrCacheUnavailable = errors.New("cache: redis unavailable, breaker open")
+
 // Cache defines an interface for getting and setting values to a cache.
 type Cache interface {
 	// Get retrieves value belonging to key from the cache.
@@ -40,113 +69,771 @@ type Cache interface {
 
 	// Set sets value in a cache under key, with an expiration.
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration, retry bool) error
+
+	// Once retrieves key from the cache into value, populating it by calling
+	// fn on a miss. Concurrent callers for the same key collapse onto a
+	// single in-flight call to fn, so only one goroutine per process ever
+	// falls through to the backing store on a miss (or while redis is
+	// unavailable); see the Redis.Once doc for the shared-value caveat.
+	Once(ctx context.Context, key string, value interface{}, expiration time.Duration, fn func() (interface{}, error)) error
+
+	// Delete removes key from the cache.
+	Delete(ctx context.Context, key string) error
+
+	// Keys returns the keys in this cache matching pattern. It is
+	// SCAN-based, never KEYS, so it never blocks redis while enumerating.
+	Keys(ctx context.Context, pattern string) ([]string, error)
+
+	// Namespace returns a child cache whose keys are transparently
+	// prefixed with name, so independent subsystems sharing this module
+	// can't collide with or enumerate each other's keys.
+	Namespace(name string) Cache
 }
 
-// NewCache returns a Cache object. If a host is provided then the underlying
-// implementation will connect to redis at that host. Otherwise, an in-memory cache will be used.
-func NewCache(host string) Cache {
-	// All values will be marshaled upon Set and unmarshaled upon Get.
-	codec := &rediscache.Codec{
-		Marshal: func(v interface{}) ([]byte, error) {
-			return msgpack.Marshal(v)
-		},
-		Unmarshal: func(b []byte, v interface{}) error {
-			return msgpack.Unmarshal(b, v)
+// LocalCache is the in-process fallback store Redis.Get/Set use when no
+// redis client is configured (useful for unit tests). When a client is
+// configured, the default TinyLFU tier steps aside in favor of redis's own
+// client-side cache (DoCache), since unlike the default tier DoCache is
+// invalidated by redis on mutation. A LocalCache supplied via
+// WithLocalCache is trusted to handle its own invalidation and is always
+// consulted, client or no.
+type LocalCache interface {
+	// Get returns the marshaled value for key, if present and unexpired.
+	Get(key string) ([]byte, bool)
+	// Set stores the marshaled value for key. ttl, if non-zero, caps how
+	// long the entry may live here, so a value set in redis with a short
+	// expiration can't be served stale out of the local tier long after
+	// redis itself has forgotten it. A zero ttl uses the tier's own
+	// default.
+	Set(key string, value []byte, ttl time.Duration)
+	// Del evicts key from the local tier.
+	Del(key string)
+}
+
+// tinyLFULocalCache is the default LocalCache, backed by a TinyLFU admission
+// policy so hot keys survive eviction pressure better than plain LRU.
+type tinyLFULocalCache struct {
+	mu  sync.Mutex
+	lfu *tinylfu.T
+	ttl time.Duration
+}
+
+func newTinyLFULocalCache(size int, ttl time.Duration) *tinyLFULocalCache {
+	return &tinyLFULocalCache{
+		lfu: tinylfu.New(size, size*10),
+		ttl: ttl,
+	}
+}
+
+func (c *tinyLFULocalCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, ok := c.lfu.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	b, ok := item.([]byte)
+	return b, ok
+}
+
+func (c *tinyLFULocalCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl <= 0 || ttl > c.ttl {
+		ttl = c.ttl
+	}
+
+	c.lfu.Set(&tinylfu.Item{
+		Key:      key,
+		Value:    value,
+		ExpireAt: time.Now().Add(ttl),
+	})
+}
+
+func (c *tinyLFULocalCache) Del(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lfu.Del(key)
+}
+
+// Option configures a Cache constructed by NewCache.
+type Option func(*options)
+
+type options struct {
+	local     LocalCache
+	localSize int
+	localTTL  time.Duration
+	codec     Codec
+
+	meterProvider  metric.MeterProvider
+	tracerProvider trace.TracerProvider
+}
+
+func defaultOptions() *options {
+	return &options{
+		localSize: localCacheSize,
+		localTTL:  defaultLocalCacheTTL,
+	}
+}
+
+// WithCodec overrides the default msgpack codec used to marshal and
+// unmarshal cache values. See MsgpackCodec, GobCodec, JSONCodec and
+// ProtoCodec for the built-in choices.
+func WithCodec(codec Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+// WithMeterProvider overrides the OpenTelemetry MeterProvider used to
+// record cache metrics. Defaults to otel.GetMeterProvider().
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(o *options) {
+		o.meterProvider = mp
+	}
+}
+
+// WithTracerProvider overrides the OpenTelemetry TracerProvider used to
+// trace cache operations. Defaults to otel.GetTracerProvider().
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithLocalCache overrides the default TinyLFU local tier with a custom
+// LocalCache strategy.
+func WithLocalCache(local LocalCache) Option {
+	return func(o *options) {
+		o.local = local
+	}
+}
+
+// WithLocalCacheSize configures the size and TTL of the default TinyLFU
+// local tier. It has no effect if WithLocalCache is also supplied.
+func WithLocalCacheSize(size int, ttl time.Duration) Option {
+	return func(o *options) {
+		o.localSize = size
+		o.localTTL = ttl
+	}
+}
+
+// Topology selects how NewCacheFromConfig connects to redis.
+type Topology int
+
+const (
+	// TopologyStandalone talks to a single redis node. Config.Addrs must
+	// hold exactly one host:port.
+	TopologyStandalone Topology = iota
+	// TopologySentinel fails over between redis nodes via a sentinel
+	// quorum. Config.Addrs holds the sentinel addresses and
+	// Config.MasterName the monitored master set.
+	TopologySentinel
+	// TopologyCluster speaks the redis cluster protocol. Config.Addrs
+	// holds cluster seed nodes.
+	TopologyCluster
+)
+
+// Config describes how NewCacheFromConfig connects to redis: topology,
+// auth, TLS, pool size and per-operation timeouts. The zero value has no
+// Addrs and NewCacheFromConfig falls back to the local cache tier only, as
+// when host is empty in NewCache.
+type Config struct {
+	// Topology selects standalone, sentinel, or cluster mode.
+	Topology Topology
+	// Addrs is the set of seed addresses: one host:port for standalone,
+	// the sentinel addresses for sentinel, or the cluster seed nodes for
+	// cluster.
+	Addrs []string
+	// MasterName is the sentinel master set name. Only used when
+	// Topology is TopologySentinel.
+	MasterName string
+
+	// Username and Password authenticate against redis (or, for
+	// sentinel, against the sentinels themselves).
+	Username string
+	Password string
+	// DB selects the logical database index. Not valid for cluster.
+	DB int
+
+	// TLSConfig enables TLS when non-nil.
+	TLSConfig *tls.Config
+
+	// PoolSize caps the number of blocking connections the client keeps
+	// open per redis node. Zero uses the client default.
+	PoolSize int
+
+	// DialTimeout bounds connecting to a redis node. Zero uses the client
+	// default. There is no separate ReadTimeout: rueidis uses a single
+	// conn-level timeout for a command round trip, so WriteTimeout covers
+	// reads too.
+	DialTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// Options configures the local cache tier; see WithLocalCache and
+	// WithLocalCacheSize.
+	Options []Option
+
+	// RetryPolicy configures the backoff between retried operations. The
+	// zero value uses defaultBaseDelay, defaultMaxDelay and defaultRetries.
+	RetryPolicy RetryPolicy
+	// BreakerPolicy configures the circuit breaker wrapping redis. The
+	// zero value uses defaultMaxFailures and defaultBreakerTimeout.
+	BreakerPolicy BreakerPolicy
+}
+
+// RetryPolicy configures the exponential backoff with full jitter used
+// between retried Get/Set attempts.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of attempts made when retry is
+	// requested. Zero uses defaultRetries.
+	MaxRetries int
+	// BaseDelay is the backoff delay after the first failed attempt.
+	// Zero uses defaultBaseDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay regardless of attempt count. Zero
+	// uses defaultMaxDelay.
+	MaxDelay time.Duration
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxRetries == 0 {
+		p.MaxRetries = defaultRetries
+	}
+	if p.BaseDelay == 0 {
+		p.BaseDelay = defaultBaseDelay
+	}
+	if p.MaxDelay == 0 {
+		p.MaxDelay = defaultMaxDelay
+	}
+	return p
+}
+
+// BreakerPolicy configures the circuit breaker that wraps the redis
+// backend so a brownout fails fast instead of amplifying load via retries.
+type BreakerPolicy struct {
+	// MaxFailures is the number of consecutive failures that trips the
+	// breaker open. Zero uses defaultMaxFailures.
+	MaxFailures uint32
+	// Timeout is how long the breaker stays open before allowing a
+	// single trial request through. Zero uses defaultBreakerTimeout.
+	Timeout time.Duration
+}
+
+func (p BreakerPolicy) withDefaults() BreakerPolicy {
+	if p.MaxFailures == 0 {
+		p.MaxFailures = defaultMaxFailures
+	}
+	if p.Timeout == 0 {
+		p.Timeout = defaultBreakerTimeout
+	}
+	return p
+}
+
+// newBreaker builds the circuit breaker wrapping redis, tripping open after
+// p.MaxFailures consecutive failures and staying open for p.Timeout. p is
+// expected to already have withDefaults applied.
+func newBreaker(p BreakerPolicy) *gobreaker.CircuitBreaker {
+	return gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:    "redis",
+		Timeout: p.Timeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= p.MaxFailures
 		},
+	})
+}
+
+// NewCacheFromConfig returns a Cache object wired up per cfg. If cfg.Addrs
+// is empty, no redis connection is made and the cache falls back to a
+// TinyLFU local tier only (useful for unit tests); see LocalCache.
+// Otherwise it connects over RESP3, using client-side caching (DoCache) so
+// hot Get calls are served out of an in-process cache that redis itself
+// invalidates on mutation — the TinyLFU tier is not consulted in this case,
+// so that invalidation guarantee isn't undercut by a second, uninvalidated
+// cache sitting in front of it.
+func NewCacheFromConfig(cfg Config) Cache {
+	o := defaultOptions()
+	for _, opt := range cfg.Options {
+		opt(o)
 	}
 
-	// Use in-memory cache if a redis server hostname is not provided.
-	if host == "" {
-		codec.UseLocalCache(localCacheSize, time.Minute*5)
-	} else {
-		client := redis.NewClient(&redis.Options{
-			Addr: host + ":" + DefaultRedisPort,
-		})
-		codec.Redis = client
+	local := o.local
+	localIsDefault := local == nil
+	if localIsDefault {
+		local = newTinyLFULocalCache(o.localSize, o.localTTL)
+	}
+
+	codec := o.codec
+	if codec == nil {
+		codec = MsgpackCodec
+	}
+
+	var client rueidis.Client
+	if len(cfg.Addrs) > 0 {
+		opt := rueidis.ClientOption{
+			InitAddress:      cfg.Addrs,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			SelectDB:         cfg.DB,
+			TLSConfig:        cfg.TLSConfig,
+			BlockingPoolSize: cfg.PoolSize,
+			ConnWriteTimeout: cfg.WriteTimeout,
+			Dialer:           net.Dialer{Timeout: cfg.DialTimeout},
+		}
+		if cfg.Topology == TopologySentinel {
+			opt.Sentinel = rueidis.SentinelOption{MasterSet: cfg.MasterName}
+		}
+
+		var err error
+		client, err = rueidis.NewClient(opt)
+		if err != nil {
+			log.Error().Msgf("failed to connect to redis at %v, falling back to local cache only: %v", cfg.Addrs, err)
+		}
+	}
+
+	breaker := newBreaker(cfg.BreakerPolicy.withDefaults())
+
+	mp := o.meterProvider
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	tp := o.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
 	}
 
 	return &Redis{
-		codec: codec,
+		client:         client,
+		local:          local,
+		localIsDefault: localIsDefault,
+		codec:          codec,
+		retry:          cfg.RetryPolicy.withDefaults(),
+		breaker:        breaker,
+		instr:          newInstrumentation(mp, tp),
+	}
+}
+
+// NewCache is a thin wrapper around NewCacheFromConfig for the common case
+// of a single standalone node on the default port, kept as the original
+// entry point for compatibility with callers that only ever spoke to one
+// host. Use NewCacheFromConfig directly for sentinel or cluster topologies.
+func NewCache(host string, opts ...Option) Cache {
+	cfg := Config{Options: opts}
+	if host != "" {
+		cfg.Addrs = []string{host + ":" + DefaultRedisPort}
 	}
+
+	return NewCacheFromConfig(cfg)
 }
 
 // Redis is an implementation of Cache with an underlying redis server.
 type Redis struct {
-	codec *rediscache.Codec
+	client         rueidis.Client
+	local          LocalCache
+	localIsDefault bool
+	codec          Codec
+	group          singleflight.Group
+	retry          RetryPolicy
+	breaker        *gobreaker.CircuitBreaker
+	instr          *instrumentation
+	namespace      string
+}
+
+// Namespace returns a child cache whose keys are transparently prefixed
+// poc/<namespace>/..., sharing this cache's client, local tier, codec,
+// breaker and instrumentation. Namespaces may be nested:
+// c.Namespace("a").Namespace("b") keys under poc/a/b/.... Metrics carry the
+// full namespace as an attribute, so per-subsystem hit rates stay visible.
+func (r *Redis) Namespace(name string) Cache {
+	ns := name
+	if r.namespace != "" {
+		ns = r.namespace + "/" + name
+	}
+
+	return &Redis{
+		client:         r.client,
+		local:          r.local,
+		localIsDefault: r.localIsDefault,
+		codec:          r.codec,
+		retry:          r.retry,
+		breaker:        r.breaker,
+		instr:          r.instr,
+		namespace:      ns,
+	}
+}
+
+// key returns the fully qualified, namespace-scoped key stored in both the
+// local tier and redis.
+func (r *Redis) key(key string) string {
+	if r.namespace == "" {
+		return fmt.Sprintf("%s/%s", keyPrefix, key)
+	}
+
+	return fmt.Sprintf("%s/%s/%s", keyPrefix, r.namespace, key)
 }
 
 // Call the redis get with retries
 func (r *Redis) Get(ctx context.Context, key string, value interface{}, retry bool) error {
-	var err error
-	retries := 1
+	ctx, span := r.instr.tracer.Start(ctx, "cache.Get")
+	defer span.End()
+
+	start := time.Now()
+	k := r.key(key)
+
+	// The default TinyLFU tier is only consulted when no redis client is
+	// configured: with a client, DoCache already gives us an in-process
+	// cache that redis itself invalidates on mutation, and layering
+	// TinyLFU in front of it would serve stale reads past that
+	// invalidation, undercutting the whole point of client-side caching.
+	// A custom LocalCache supplied via WithLocalCache is trusted to
+	// manage its own invalidation and is always consulted.
+	if r.client == nil || !r.localIsDefault {
+		if b, ok := r.local.Get(k); ok {
+			err := r.codec.Unmarshal(b, value)
+			if err != nil {
+				err = &codecError{err: err}
+				span.RecordError(err)
+			}
+			r.recordGet(ctx, true, start, err)
+			return err
+		}
+	}
+
+	attempts := 1
 	if retry {
-		retries = defaultRetries
+		attempts = r.retry.MaxRetries
 	}
 
-	for i := 0; i < retries; i++ {
-		// do not retry if no value is present
-		if err = r.get(key, value); err == nil {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			if werr := waitBackoff(ctx, r.retry, i); werr != nil {
+				return werr
+			}
+		}
+
+		// do not retry if no value is present, or the breaker is open
+		if err = r.get(ctx, key, value); err == nil {
+			// Only backfill a custom LocalCache here: a successful r.get
+			// only happens when a client is configured, and the default
+			// TinyLFU tier would just be a second, uninvalidated copy on
+			// top of what DoCache already cached for us.
+			if !r.localIsDefault {
+				r.backfillLocal(k, value, 0)
+			}
+			r.recordGet(ctx, false, start, nil)
 			return nil
-		} else if err == ErrCacheMiss {
+		} else if err == ErrCacheMiss || err == ErrCacheUnavailable {
+			r.recordGet(ctx, false, start, err)
 			return err
 		}
 
 		log.Debug().Msgf("failed to get cache item. retry #%v for key: %s, with error: %v", i, key, err)
 	}
 
+	span.RecordError(err)
+	r.recordGet(ctx, false, start, err)
+
 	return err
 }
 
 // Call the redis set with retries
 func (r *Redis) Set(ctx context.Context, key string, value interface{}, expiration time.Duration, retry bool) error {
-	var err error
-	retries := 1
+	ctx, span := r.instr.tracer.Start(ctx, "cache.Set")
+	defer span.End()
+
+	start := time.Now()
+
+	attempts := 1
 	if retry {
-		retries = defaultRetries
+		attempts = r.retry.MaxRetries
 	}
 
-	for i := 0; i < retries; i++ {
-		if err = r.set(key, value, expiration); err == nil {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			if werr := waitBackoff(ctx, r.retry, i); werr != nil {
+				return werr
+			}
+		}
+
+		if err = r.set(ctx, key, value, expiration); err == nil {
+			// Skip the default TinyLFU tier here: with a client, DoCache
+			// caches redis reads itself, so writing our own copy into it
+			// on top would reintroduce the stale-read window DoCache
+			// exists to close. A custom LocalCache is trusted to manage
+			// its own invalidation, so it's always kept up to date, capped
+			// to the caller's expiration so it can't outlive what the
+			// caller asked for.
+			if r.client == nil || !r.localIsDefault {
+				r.backfillLocal(r.key(key), value, expiration)
+			}
+			r.recordOp(ctx, start, nil)
 			return nil
+		} else if err == ErrCacheUnavailable {
+			r.recordOp(ctx, start, err)
+			return err
 		}
 
 		log.Debug().Msgf("failed to set cache item. retry #%v for key: %s, with error: %v", i, key, err)
 	}
 
+	span.RecordError(err)
+	r.recordOp(ctx, start, err)
+
 	return err
 }
 
-// Get retrieves key from the cache and reads it into value.
-// A pointer type should be passed into value.
-func (r *Redis) get(key string, value interface{}) error {
-	err := r.codec.Get(keyWithPrefix(key), &value)
+// waitBackoff sleeps for an exponentially increasing, fully-jittered delay
+// ahead of the next retry attempt, or returns ctx.Err() if ctx ends first.
+func waitBackoff(ctx context.Context, p RetryPolicy, attempt int) error {
+	delay := p.BaseDelay << uint(attempt-1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Duration(rand.Int63n(int64(delay)))):
+		return nil
+	}
+}
+
+// Once retrieves key from the cache into value, populating it via fn on a
+// miss. Concurrent callers for the same key collapse onto a single
+// in-flight call to fn via singleflight, so only one goroutine per process
+// ever falls through to the backing store. This still holds when redis is
+// unavailable: a circuit-breaker trip (ErrCacheUnavailable) falls through
+// to fn exactly like a miss, just skipping the cache write-back, so a
+// redis brownout collapses onto fn instead of disabling the very
+// protection Once exists to provide.
+//
+// Every caller collapsed onto the same fn call receives the same value via
+// assign, not an independent copy: if fn returns a pointer, slice or map,
+// all of them share the underlying data, and one caller mutating it
+// corrupts the rest. Callers whose result is a mutable type should treat it
+// as read-only, or have fn return a value type.
+func (r *Redis) Once(ctx context.Context, key string, value interface{}, expiration time.Duration, fn func() (interface{}, error)) error {
+	ctx, span := r.instr.tracer.Start(ctx, "cache.Once")
+	defer span.End()
+
+	err := r.Get(ctx, key, value, true)
+	if err == nil {
+		return nil
+	} else if err != ErrCacheMiss && err != ErrCacheUnavailable {
+		return err
+	}
+
+	v, err, _ := r.group.Do(r.key(key), func() (interface{}, error) {
+		loaded, err := fn()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.Set(ctx, key, loaded, expiration, true); err != nil {
+			log.Debug().Msgf("failed to cache loaded value for key: %s, with error: %v", key, err)
+		}
+
+		return loaded, nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return assign(value, v)
+}
+
+// get retrieves key from redis via a client-side-cached command and reads
+// it into value. A pointer type should be passed into value. Calls go
+// through the circuit breaker, which returns ErrCacheUnavailable in place
+// of the underlying error once it has tripped open.
+func (r *Redis) get(ctx context.Context, key string, value interface{}) error {
+	if r.client == nil {
+		return ErrCacheMiss
+	}
+
+	var miss bool
+	res, err := r.breaker.Execute(func() (interface{}, error) {
+		cmd := r.client.B().Get().Key(r.key(key)).Cache()
+		b, err := r.client.DoCache(ctx, cmd, defaultLocalCacheTTL).AsBytes()
+		if rueidis.IsRedisNil(err) {
+			// A miss is not a breaker failure; it's an expected outcome.
+			miss = true
+			return nil, nil
+		}
+
+		return b, err
+	})
 	if err != nil {
-		// Use this package's error value to hide the redis implementation.
-		if err == rediscache.ErrCacheMiss {
-			return ErrCacheMiss
+		if err == gobreaker.ErrOpenState || err == gobreaker.ErrTooManyRequests {
+			return ErrCacheUnavailable
 		}
 
 		return err
 	}
 
+	if miss {
+		return ErrCacheMiss
+	}
+
+	b := res.([]byte)
+	r.instr.bytesIn.Add(ctx, int64(len(b)), metric.WithAttributes(attribute.String("namespace", r.namespace)))
+
+	if err := r.codec.Unmarshal(b, value); err != nil {
+		return &codecError{err: err}
+	}
+
 	return nil
 }
 
-// Set adds value to the cache. If no expiration is supplied, default to 2 hours.
-func (r *Redis) set(key string, value interface{}, expiration time.Duration) error {
+// set adds value to redis. If no expiration is supplied, default to 2 hours.
+// Calls go through the circuit breaker; see get.
+func (r *Redis) set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if r.client == nil {
+		return nil
+	}
+
 	if expiration == 0 {
 		expiration = defaultExpiration
 	}
 
-	item := &rediscache.Item{
-		Key:        keyWithPrefix(key),
-		Object:     value,
-		Expiration: expiration,
+	b, err := r.codec.Marshal(value)
+	if err != nil {
+		return &codecError{err: err}
+	}
+
+	r.instr.bytesOut.Add(ctx, int64(len(b)), metric.WithAttributes(attribute.String("namespace", r.namespace)))
+
+	_, err = r.breaker.Execute(func() (interface{}, error) {
+		cmd := r.client.B().Setex().Key(r.key(key)).Seconds(int64(expiration.Seconds())).Value(rueidis.BinaryString(b)).Build()
+		return nil, r.client.Do(ctx, cmd).Error()
+	})
+	if err == gobreaker.ErrOpenState || err == gobreaker.ErrTooManyRequests {
+		return ErrCacheUnavailable
 	}
 
-	return r.codec.Set(item)
+	return err
 }
 
-func keyWithPrefix(key string) string {
-	return fmt.Sprintf("%s/%s", keyPrefix, key)
+// Delete removes key from both the local tier and redis.
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	ctx, span := r.instr.tracer.Start(ctx, "cache.Delete")
+	defer span.End()
+
+	start := time.Now()
+	k := r.key(key)
+	r.local.Del(k)
+
+	if r.client == nil {
+		r.recordOp(ctx, start, nil)
+		return nil
+	}
+
+	_, err := r.breaker.Execute(func() (interface{}, error) {
+		cmd := r.client.B().Del().Key(k).Build()
+		return nil, r.client.Do(ctx, cmd).Error()
+	})
+	if err == gobreaker.ErrOpenState || err == gobreaker.ErrTooManyRequests {
+		err = ErrCacheUnavailable
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	r.recordOp(ctx, start, err)
+
+	return err
+}
+
+// Keys returns the keys in this cache matching pattern, scoped to this
+// cache's namespace. It walks redis with SCAN rather than KEYS so a large
+// keyspace never blocks the server while being enumerated.
+func (r *Redis) Keys(ctx context.Context, pattern string) ([]string, error) {
+	if r.client == nil {
+		return nil, nil
+	}
+
+	prefix := r.key("")
+	match := r.key(pattern)
+
+	var keys []string
+	var cursor uint64
+	for {
+		res, err := r.breaker.Execute(func() (interface{}, error) {
+			cmd := r.client.B().Scan().Cursor(cursor).Match(match).Build()
+			return r.client.Do(ctx, cmd).ToArray()
+		})
+		if err != nil {
+			if err == gobreaker.ErrOpenState || err == gobreaker.ErrTooManyRequests {
+				return nil, ErrCacheUnavailable
+			}
+
+			return nil, err
+		}
+
+		entries := res.([]rueidis.RedisMessage)
+
+		// SCAN always replies with the cursor as a bulk string, even over
+		// RESP3, so it must be read with AsUint64 rather than ToInt64.
+		next, err := entries[0].AsUint64()
+		if err != nil {
+			return nil, err
+		}
+
+		batch, err := entries[1].ToArray()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, e := range batch {
+			s, err := e.ToString()
+			if err != nil {
+				return nil, err
+			}
+
+			keys = append(keys, strings.TrimPrefix(s, prefix))
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return keys, nil
+}
+
+// backfillLocal stores value in the local tier under the already-prefixed
+// key k, capped to ttl (0 uses the tier's own default TTL), logging rather
+// than failing the caller if it cannot be marshaled.
+func (r *Redis) backfillLocal(k string, value interface{}, ttl time.Duration) {
+	b, err := r.codec.Marshal(value)
+	if err != nil {
+		log.Debug().Msgf("failed to backfill local cache for key: %s, with error: %v", k, err)
+		return
+	}
+
+	r.local.Set(k, b, ttl)
+}
+
+// assign copies src into dst, which must be a non-nil pointer. It is used to
+// hand a singleflight-shared result back to each waiting caller of Once.
+func assign(dst interface{}, src interface{}) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("cache: Once value must be a non-nil pointer")
+	}
+
+	sv := reflect.ValueOf(src)
+	if !sv.Type().AssignableTo(dv.Elem().Type()) {
+		return fmt.Errorf("cache: cannot assign %T into %T", src, dst)
+	}
+
+	dv.Elem().Set(sv)
+	return nil
 }
@@ -2,15 +2,21 @@ package main
 
 import (
 	"fmt"
-	"time"
 	"net/http"
-	cache "github.com/NYTimes/mercury-poc/cache"
+	"time"
+
+	cache "github.com/NYTimes/mercury-poc"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
 const (
 	redisHost = "10.180.141.36"
+	addr      = ":8080"
 )
 
 func main() {
@@ -18,31 +24,39 @@ func main() {
 
 	start := time.Now()
 
-	c := cache.NewCache(redisHost)
+	promExporter, err := prometheus.New()
+	if err != nil {
+		log.Fatal().Msgf("failed to create prometheus exporter: %v", err)
+	}
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(promExporter))
+	otel.SetMeterProvider(mp)
+
+	c := cache.NewCache(redisHost, cache.WithMeterProvider(mp))
 
 	r := mux.NewRouter()
 	r.HandleFunc("/healthz", handleHealth)
-	r.HandleFunc("/read/cache", handleCache)
+	r.HandleFunc("/read/cache", handleCache(c))
 	r.HandleFunc("/read/db", handleDb)
+	r.Handle("/metrics", promhttp.Handler())
 	http.Handle("/", r)
 
 	fmt.Printf("Send Compiler is up and running in %v!\n", time.Since(start).Seconds())
-}
 
-func (s *Server) handleHealth() httprouter.Handle {
-	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-		_, _ = w.Write([]byte("OK"))
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		log.Fatal().Msgf("server exited: %v", err)
 	}
 }
 
-func (s *Server) handleCache() httprouter.Handle {
-	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
-		_, _ = w.Write([]byte("OK"))
-	}
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("OK"))
 }
 
-func (s *Server) handleDb() httprouter.Handle {
-	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+func handleCache(c cache.Cache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte("OK"))
 	}
 }
+
+func handleDb(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("OK"))
+}
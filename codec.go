@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v4"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec marshals and unmarshals cache values to and from the bytes stored
+// in the local tier and in redis.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(b []byte, v interface{}) error
+}
+
+// MsgpackCodec is the default Codec, kept for wire compatibility with
+// existing cache entries.
+var MsgpackCodec Codec = msgpackCodec{}
+
+// GobCodec marshals values with encoding/gob, for callers whose domain
+// types are already gob-encoded elsewhere.
+var GobCodec Codec = gobCodec{}
+
+// JSONCodec marshals values with encoding/json, for sharing the redis
+// keyspace with services that don't speak msgpack or gob.
+var JSONCodec Codec = jsonCodec{}
+
+// ProtoCodec marshals values with protobuf wire encoding. Marshal and
+// Unmarshal require v to implement proto.Message.
+var ProtoCodec Codec = protoCodec{}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (msgpackCodec) Unmarshal(b []byte, v interface{}) error {
+	return msgpack.Unmarshal(b, v)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(b []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(b)).Decode(v)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(b []byte, v interface{}) error {
+	return json.Unmarshal(b, v)
+}
+
+type protoCodec struct{}
+
+func (protoCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("cache: ProtoCodec requires a proto.Message, got %T", v)
+	}
+
+	return proto.Marshal(m)
+}
+
+func (protoCodec) Unmarshal(b []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("cache: ProtoCodec requires a proto.Message, got %T", v)
+	}
+
+	return proto.Unmarshal(b, m)
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type codecValue struct {
+	Name  string
+	Count int
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		codec Codec
+	}{
+		{"msgpack", MsgpackCodec},
+		{"gob", GobCodec},
+		{"json", JSONCodec},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := codecValue{Name: "widget", Count: 3}
+
+			b, err := tt.codec.Marshal(in)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			var out codecValue
+			if err := tt.codec.Unmarshal(b, &out); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+
+			if out != in {
+				t.Errorf("round trip = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
+
+func TestProtoCodec_RoundTrip(t *testing.T) {
+	in := wrapperspb.String("widget")
+
+	b, err := ProtoCodec.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	out := &wrapperspb.StringValue{}
+	if err := ProtoCodec.Unmarshal(b, out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !proto.Equal(in, out) {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestProtoCodec_RequiresProtoMessage(t *testing.T) {
+	if _, err := ProtoCodec.Marshal(codecValue{Name: "widget"}); err == nil {
+		t.Error("Marshal with a non-proto.Message value: want error, got nil")
+	}
+
+	if err := ProtoCodec.Unmarshal([]byte("x"), &codecValue{}); err == nil {
+		t.Error("Unmarshal into a non-proto.Message value: want error, got nil")
+	}
+}
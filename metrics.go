@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/NYTimes/mercury-poc/cache"
+
+// instrumentation holds the OpenTelemetry instruments used to record cache
+// operations: a tracer for spans, and counters/histograms for hit/miss
+// rate, error kind, latency and bytes in/out.
+type instrumentation struct {
+	tracer trace.Tracer
+
+	hits      metric.Int64Counter
+	misses    metric.Int64Counter
+	localHits metric.Int64Counter
+	errs      metric.Int64Counter
+	latency   metric.Float64Histogram
+	bytesIn   metric.Int64Counter
+	bytesOut  metric.Int64Counter
+}
+
+func newInstrumentation(mp metric.MeterProvider, tp trace.TracerProvider) *instrumentation {
+	meter := mp.Meter(instrumentationName)
+
+	hits, _ := meter.Int64Counter("cache.hits", metric.WithDescription("cache hits, served from either tier"))
+	misses, _ := meter.Int64Counter("cache.misses", metric.WithDescription("cache misses"))
+	localHits, _ := meter.Int64Counter("cache.local_hits", metric.WithDescription("hits served from the local tier; compare against cache.hits for local hit ratio"))
+	errs, _ := meter.Int64Counter("cache.errors", metric.WithDescription("cache operation errors, split by kind"))
+	latency, _ := meter.Float64Histogram("cache.operation.duration", metric.WithDescription("cache operation latency"), metric.WithUnit("ms"))
+	bytesIn, _ := meter.Int64Counter("cache.bytes_in", metric.WithDescription("bytes read from redis"))
+	bytesOut, _ := meter.Int64Counter("cache.bytes_out", metric.WithDescription("bytes written to redis"))
+
+	return &instrumentation{
+		tracer:    tp.Tracer(instrumentationName),
+		hits:      hits,
+		misses:    misses,
+		localHits: localHits,
+		errs:      errs,
+		latency:   latency,
+		bytesIn:   bytesIn,
+		bytesOut:  bytesOut,
+	}
+}
+
+// codecError wraps a Codec Marshal/Unmarshal failure so errKind can tell a
+// serialization failure apart from a redis or network error.
+type codecError struct {
+	err error
+}
+
+func (e *codecError) Error() string { return e.err.Error() }
+func (e *codecError) Unwrap() error { return e.err }
+
+// errKind classifies err for the cache.errors counter. Misses are reported
+// via cache.misses instead and are not a "kind" here.
+func errKind(err error) string {
+	var ce *codecError
+	switch {
+	case err == nil:
+		return ""
+	case err == ErrCacheUnavailable:
+		return "breaker-open"
+	case errors.As(err, &ce):
+		return "serialization"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "other"
+	}
+}
+
+// recordGet records the outcome of a Get/Once attempt: hit/miss/error
+// counters, the local-tier hit counter, and operation latency.
+func (r *Redis) recordGet(ctx context.Context, local bool, start time.Time, err error) {
+	attrs := metric.WithAttributes(attribute.String("namespace", r.namespace))
+
+	switch {
+	case err == nil:
+		r.instr.hits.Add(ctx, 1, attrs)
+		if local {
+			r.instr.localHits.Add(ctx, 1, attrs)
+		}
+	case err == ErrCacheMiss:
+		r.instr.misses.Add(ctx, 1, attrs)
+	default:
+		r.recordErr(ctx, err)
+	}
+
+	r.instr.latency.Record(ctx, float64(time.Since(start).Milliseconds()), attrs)
+}
+
+// recordOp records the latency and, on failure, the error counter for a
+// Set/Delete attempt. Unlike recordGet, there is no hit/miss distinction.
+func (r *Redis) recordOp(ctx context.Context, start time.Time, err error) {
+	if err != nil {
+		r.recordErr(ctx, err)
+	}
+
+	r.instr.latency.Record(ctx, float64(time.Since(start).Milliseconds()), metric.WithAttributes(
+		attribute.String("namespace", r.namespace),
+	))
+}
+
+// recordErr increments cache.errors for a non-miss error, if it is
+// classifiable; unclassified errors still surface via span.RecordError.
+func (r *Redis) recordErr(ctx context.Context, err error) {
+	kind := errKind(err)
+	if kind == "" {
+		return
+	}
+
+	r.instr.errs.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("namespace", r.namespace),
+		attribute.String("kind", kind),
+	))
+}
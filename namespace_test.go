@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRedis_Key(t *testing.T) {
+	root := NewCacheFromConfig(Config{}).(*Redis)
+	if got, want := root.key("foo"), "poc/foo"; got != want {
+		t.Errorf("root.key(%q) = %q, want %q", "foo", got, want)
+	}
+
+	child := root.Namespace("users").(*Redis)
+	if got, want := child.key("foo"), "poc/users/foo"; got != want {
+		t.Errorf("child.key(%q) = %q, want %q", "foo", got, want)
+	}
+
+	grandchild := child.Namespace("sessions").(*Redis)
+	if got, want := grandchild.key("foo"), "poc/users/sessions/foo"; got != want {
+		t.Errorf("grandchild.key(%q) = %q, want %q", "foo", got, want)
+	}
+}
+
+func TestNamespace_KeysDoNotCollide(t *testing.T) {
+	root := NewCacheFromConfig(Config{})
+	users := root.Namespace("users")
+	orders := root.Namespace("orders")
+
+	ctx := context.Background()
+
+	if err := users.Set(ctx, "1", "alice", time.Minute, false); err != nil {
+		t.Fatalf("users.Set: %v", err)
+	}
+	if err := orders.Set(ctx, "1", "widget", time.Minute, false); err != nil {
+		t.Fatalf("orders.Set: %v", err)
+	}
+
+	var user string
+	if err := users.Get(ctx, "1", &user, false); err != nil {
+		t.Fatalf("users.Get: %v", err)
+	}
+	if user != "alice" {
+		t.Errorf("users.Get(1) = %q, want %q", user, "alice")
+	}
+
+	var order string
+	if err := orders.Get(ctx, "1", &order, false); err != nil {
+		t.Fatalf("orders.Get: %v", err)
+	}
+	if order != "widget" {
+		t.Errorf("orders.Get(1) = %q, want %q", order, "widget")
+	}
+}
+
+func TestNamespace_SharesUnderlyingTiers(t *testing.T) {
+	root := NewCacheFromConfig(Config{}).(*Redis)
+	child := root.Namespace("users").(*Redis)
+
+	if child.local != root.local {
+		t.Error("Namespace() did not share the parent's local tier")
+	}
+	if child.breaker != root.breaker {
+		t.Error("Namespace() did not share the parent's breaker")
+	}
+	if child.instr != root.instr {
+		t.Error("Namespace() did not share the parent's instrumentation")
+	}
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOnce_CollapsesConcurrentLoads(t *testing.T) {
+	c := NewCacheFromConfig(Config{})
+
+	var calls int32
+	fn := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return "loaded", nil
+	}
+
+	const goroutines = 20
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+
+	results := make([]string, goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			var value string
+			if err := c.Once(context.Background(), "shared-key", &value, time.Minute, fn); err != nil {
+				t.Errorf("Once: %v", err)
+				return
+			}
+			results[i] = value
+		}(i)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("fn called %d times, want exactly 1", got)
+	}
+
+	for i, r := range results {
+		if r != "loaded" {
+			t.Errorf("result[%d] = %q, want %q", i, r, "loaded")
+		}
+	}
+}
+
+func TestOnce_SkipsLoaderOnHit(t *testing.T) {
+	c := NewCacheFromConfig(Config{})
+	ctx := context.Background()
+
+	if err := c.Set(ctx, "cached-key", "from-set", time.Minute, false); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	called := false
+	var value string
+	err := c.Once(ctx, "cached-key", &value, time.Minute, func() (interface{}, error) {
+		called = true
+		return "from-fn", nil
+	})
+	if err != nil {
+		t.Fatalf("Once: %v", err)
+	}
+
+	if called {
+		t.Error("Once called the loader despite a cache hit")
+	}
+	if value != "from-set" {
+		t.Errorf("value = %q, want %q", value, "from-set")
+	}
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+func TestWaitBackoff_RespectsContext(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Hour, MaxDelay: time.Hour}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := waitBackoff(ctx, p, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("waitBackoff with a canceled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestWaitBackoff_CapsAtMaxDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	start := time.Now()
+	if err := waitBackoff(context.Background(), p, 10); err != nil {
+		t.Fatalf("waitBackoff: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("waitBackoff(attempt=10) took %v, want capped near MaxDelay", elapsed)
+	}
+}
+
+func TestRetryPolicy_withDefaults(t *testing.T) {
+	p := RetryPolicy{}.withDefaults()
+
+	if p.MaxRetries != defaultRetries {
+		t.Errorf("MaxRetries = %v, want %v", p.MaxRetries, defaultRetries)
+	}
+	if p.BaseDelay != defaultBaseDelay {
+		t.Errorf("BaseDelay = %v, want %v", p.BaseDelay, defaultBaseDelay)
+	}
+	if p.MaxDelay != defaultMaxDelay {
+		t.Errorf("MaxDelay = %v, want %v", p.MaxDelay, defaultMaxDelay)
+	}
+
+	// Explicit values are left untouched.
+	custom := RetryPolicy{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: time.Minute}.withDefaults()
+	if custom != (RetryPolicy{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: time.Minute}) {
+		t.Errorf("withDefaults overrode explicit RetryPolicy: %+v", custom)
+	}
+}
+
+func TestNewBreaker_TripsOpenAfterMaxFailures(t *testing.T) {
+	p := BreakerPolicy{MaxFailures: 2, Timeout: time.Minute}
+	b := newBreaker(p)
+
+	failing := errors.New("boom")
+	call := func() error {
+		_, err := b.Execute(func() (interface{}, error) { return nil, failing })
+		return err
+	}
+
+	if err := call(); err != failing {
+		t.Fatalf("1st failure = %v, want underlying error", err)
+	}
+	if err := call(); err != failing {
+		t.Fatalf("2nd failure = %v, want underlying error", err)
+	}
+
+	// The breaker should now be open and fail fast without invoking fn.
+	called := false
+	_, err := b.Execute(func() (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+
+	if err != gobreaker.ErrOpenState {
+		t.Fatalf("3rd call error = %v, want ErrOpenState", err)
+	}
+	if called {
+		t.Error("breaker invoked fn while open; expected fail-fast")
+	}
+}
+
+func TestBreakerPolicy_withDefaults(t *testing.T) {
+	p := BreakerPolicy{}.withDefaults()
+
+	if p.MaxFailures != defaultMaxFailures {
+		t.Errorf("MaxFailures = %v, want %v", p.MaxFailures, defaultMaxFailures)
+	}
+	if p.Timeout != defaultBreakerTimeout {
+		t.Errorf("Timeout = %v, want %v", p.Timeout, defaultBreakerTimeout)
+	}
+}